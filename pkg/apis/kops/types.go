@@ -0,0 +1,111 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kops
+
+// Cluster represents a cluster object in the kops API.
+type Cluster struct {
+	Spec ClusterSpec
+}
+
+// ClusterSpec defines the configuration for a cluster.
+type ClusterSpec struct {
+	// ContainerRuntime selects the CRI implementation nodes provision, e.g.
+	// "containerd" (the default) or "crio".
+	ContainerRuntime string
+
+	// Containerd is the configuration for the containerd container runtime.
+	Containerd *ContainerdConfig
+
+	// Crio is the configuration for the CRI-O container runtime.
+	Crio *CrioConfig
+
+	// Assets configures how cloudup resolves and verifies the runtime and
+	// image assets nodes provision.
+	Assets *AssetsSpec
+}
+
+// ContainerdConfig is the configuration for the containerd container runtime.
+type ContainerdConfig struct {
+	// Version is the containerd version to install, e.g. "1.5.5".
+	Version *string
+	// Packages overrides the URL and hash used to fetch the containerd
+	// release package, bypassing version-based asset resolution entirely.
+	Packages *PackagesConfig
+	// ResolveFromUpstream allows resolving a containerd version that isn't
+	// present in the built-in hash tables by reading its sha256 from the
+	// checksum manifest on the containerd GitHub release. Disabled by
+	// default so air-gapped clusters aren't affected.
+	//
+	// This also gates using containerd's native arm64 release build: since
+	// there's no way to hand-verify an upstream arm64 hash without actually
+	// downloading it, arm64 falls back to Docker's bundled containerd
+	// unless this is set, even for versions that do publish an official
+	// arm64 build.
+	ResolveFromUpstream *bool
+}
+
+// PackagesConfig pins the exact URL and sha256 hash to use for a runtime
+// package on each architecture, shared by the containerd and CRI-O configs.
+type PackagesConfig struct {
+	UrlAmd64  *string
+	HashAmd64 *string
+	UrlArm64  *string
+	HashArm64 *string
+}
+
+// CrioConfig is the configuration for the CRI-O container runtime.
+type CrioConfig struct {
+	// Version is the CRI-O version to install, e.g. "1.23.0".
+	Version *string
+	// Packages overrides the URL and hash used to fetch the CRI-O static
+	// bundle, bypassing version-based asset resolution entirely.
+	Packages *PackagesConfig
+	// ResolveFromUpstream allows resolving a CRI-O version by reading its
+	// sha256 from the checksum manifest on the CRI-O GitHub release, since
+	// there is no kops-maintained hash table for CRI-O. Disabled by default
+	// so air-gapped clusters aren't affected.
+	ResolveFromUpstream *bool
+}
+
+// AssetsSpec configures how cloudup resolves and verifies the runtime and
+// image assets nodes provision.
+type AssetsSpec struct {
+	// OciMirror is an OCI-registry asset source, e.g.
+	// "oci://registry.example.com/kops-assets", used instead of fetching
+	// runtime tarballs directly from their upstream GitHub releases.
+	OciMirror *string
+
+	// VerifySignatures requires runtime assets resolved by version to have
+	// a valid cosign keyless signature in the public Rekor log before
+	// they're accepted.
+	VerifySignatures *bool
+
+	// TrustPolicies pins the expected Sigstore signing identity per
+	// component (e.g. "containerd", "crio"), overriding the built-in
+	// default for that component.
+	TrustPolicies map[string]SigstoreTrustPolicy
+}
+
+// SigstoreTrustPolicy pins the OIDC identity a cosign keyless signature
+// must chain back to before an asset is accepted.
+type SigstoreTrustPolicy struct {
+	// Issuer is the expected OIDC issuer, e.g. "https://token.actions.githubusercontent.com".
+	Issuer string
+	// Subject is the expected signing identity, e.g.
+	// "https://github.com/containerd/containerd/.github/workflows/release.yml@refs/heads/main".
+	Subject string
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"testing"
+
+	"k8s.io/kops/pkg/assets"
+)
+
+func TestUpstreamAssetCacheForIsScopedPerBuilder(t *testing.T) {
+	builderA := &assets.AssetBuilder{}
+	builderB := &assets.AssetBuilder{}
+
+	cacheA := upstreamAssetCacheFor(builderA)
+	cacheB := upstreamAssetCacheFor(builderB)
+
+	if cacheA == cacheB {
+		t.Fatal("upstreamAssetCacheFor returned the same cache for two different AssetBuilders")
+	}
+
+	cacheA.Store("key", "value")
+	if again := upstreamAssetCacheFor(builderA); again != cacheA {
+		t.Fatal("upstreamAssetCacheFor returned a different cache on a second call for the same AssetBuilder")
+	}
+}
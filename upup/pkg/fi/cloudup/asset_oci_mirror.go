@@ -0,0 +1,170 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/registry/remote"
+
+	"k8s.io/kops/util/pkg/architectures"
+)
+
+// ociLayerMediaType is the media type of the single-layer OCI artifact each
+// runtime tarball is mirrored as.
+const ociLayerMediaType = "application/vnd.oci.image.layer.v1.tar+gzip"
+
+// ociMirrorCacheDir is where the layer is pulled to verify it actually
+// resolves before it's trusted as the cluster's asset, keyed by digest so
+// repeat resolutions for the same component/version/arch don't re-pull.
+const ociMirrorCacheDir = "kops-oci-assets"
+
+// resolveFromOciMirror resolves the runtime tarball for component/version/arch
+// from an OCI registry mirror (e.g. Harbor, ECR, Artifact Registry) instead
+// of GitHub, using a predictable <mirror>/<component>:<version>-<arch> repo
+// layout. The tag must resolve to a single-layer manifest; the layer is
+// pulled once to a local cache to confirm the digest is actually reachable,
+// the same way resolveUpstreamAsset fetches a checksums file before
+// trusting a GitHub URL. The URL handed back, though, is the registry's own
+// blob endpoint (plain HTTP(S), per the OCI distribution spec), not the
+// local cache path: the cache only exists on the machine running `kops
+// update cluster`, and it's the provisioned node that needs to fetch this
+// asset, exactly like it does for the GitHub-hosted containerd/crio
+// tarballs. The descriptor digest is returned as the asset hash, so callers
+// can pass the result straight to findAssetsUrlHash like any other asset
+// source.
+func resolveFromOciMirror(ctx context.Context, mirror string, component string, version string, arch architectures.Architecture) (string, string, error) {
+	repoName := strings.TrimPrefix(mirror, "oci://") + "/" + component
+	repo, err := remote.NewRepository(repoName)
+	if err != nil {
+		return "", "", fmt.Errorf("connecting to OCI mirror repository %s: %w", repoName, err)
+	}
+
+	tag := fmt.Sprintf("%s-%s", version, arch)
+	return resolveFromOciMirrorRepo(ctx, repo, component, tag)
+}
+
+// resolveFromOciMirrorRepo does the actual resolution work against an
+// already-constructed repo, split out from resolveFromOciMirror so tests
+// can hand it a repo with PlainHTTP set, pointed at a fake registry;
+// resolveFromOciMirror always talks HTTPS to the registry.
+func resolveFromOciMirrorRepo(ctx context.Context, repo *remote.Repository, component string, tag string) (string, string, error) {
+	_, manifestBytes, err := oras.FetchBytes(ctx, repo, tag, oras.DefaultFetchBytesOptions)
+	if err != nil {
+		return "", "", fmt.Errorf("fetching manifest for %s:%s from OCI mirror %s: %w", component, tag, repo.Reference.Registry, err)
+	}
+
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return "", "", fmt.Errorf("parsing OCI manifest for %s:%s: %w", component, tag, err)
+	}
+	layer, err := singleRuntimeLayer(manifest)
+	if err != nil {
+		return "", "", fmt.Errorf("%s:%s: %w", component, tag, err)
+	}
+
+	cacheDir := filepath.Join(os.TempDir(), ociMirrorCacheDir, component)
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		return "", "", fmt.Errorf("creating OCI mirror cache dir %s: %w", cacheDir, err)
+	}
+
+	assetHash := layer.Digest.Encoded()
+	localPath := filepath.Join(cacheDir, assetHash)
+
+	if _, err := os.Stat(localPath); os.IsNotExist(err) {
+		if err := fetchOciLayer(ctx, repo, layer, localPath); err != nil {
+			return "", "", fmt.Errorf("pulling layer %s for %s:%s from OCI mirror %s: %w", layer.Digest, component, tag, repo.Reference.Registry, err)
+		}
+	} else if err != nil {
+		return "", "", fmt.Errorf("checking cached layer %s: %w", localPath, err)
+	}
+
+	return ociBlobUrl(repo, layer), assetHash, nil
+}
+
+// ociBlobUrl returns the plain HTTP(S) URL a node can use to fetch layer
+// directly from the registry, per the OCI distribution spec's blob-pull
+// endpoint (GET /v2/<name>/blobs/<digest>). This is what makes the asset
+// node-fetchable: nodeup downloads it the same way it downloads any other
+// HTTPS asset, without needing an OCI registry client of its own.
+func ociBlobUrl(repo *remote.Repository, layer ocispec.Descriptor) string {
+	scheme := "https"
+	if repo.PlainHTTP {
+		scheme = "http"
+	}
+	return fmt.Sprintf("%s://%s/v2/%s/blobs/%s", scheme, repo.Reference.Registry, repo.Reference.Repository, layer.Digest)
+}
+
+// singleRuntimeLayer validates that manifest describes exactly one layer,
+// of the expected runtime-tarball media type, and returns its descriptor.
+func singleRuntimeLayer(manifest ocispec.Manifest) (ocispec.Descriptor, error) {
+	if len(manifest.Layers) != 1 {
+		return ocispec.Descriptor{}, fmt.Errorf("expected a single layer, got %d", len(manifest.Layers))
+	}
+	layer := manifest.Layers[0]
+	if layer.MediaType != ociLayerMediaType {
+		return ocispec.Descriptor{}, fmt.Errorf("unexpected layer media type %q, want %q", layer.MediaType, ociLayerMediaType)
+	}
+	return layer, nil
+}
+
+// fetchOciLayer downloads a single layer blob, verifies it against
+// layer.Digest, and writes it to localPath. The digest check matters here
+// precisely because this cache is the thing deciding whether a given
+// version/arch/mirror is trustworthy before it's handed back to the asset
+// builder: a truncated read or a compromised mirror must not silently land
+// in the cache under a digest it doesn't match. The temp file is unique per
+// call so two concurrent resolves for the same layer (e.g. concurrent `kops
+// update cluster` runs) can't race on the same path.
+func fetchOciLayer(ctx context.Context, repo *remote.Repository, layer ocispec.Descriptor, localPath string) error {
+	rc, err := repo.Fetch(ctx, layer)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	f, err := os.CreateTemp(filepath.Dir(localPath), filepath.Base(localPath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := f.Name()
+
+	verifier := layer.Digest.Verifier()
+	if _, err := io.Copy(f, io.TeeReader(rc, verifier)); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if !verifier.Verified() {
+		os.Remove(tmpPath)
+		return fmt.Errorf("downloaded layer does not match expected digest %s", layer.Digest)
+	}
+
+	return os.Rename(tmpPath, localPath)
+}
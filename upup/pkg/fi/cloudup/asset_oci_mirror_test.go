@@ -0,0 +1,146 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry/remote"
+
+	"k8s.io/kops/util/pkg/architectures"
+)
+
+// fakeOciRegistry serves just enough of the OCI distribution spec (manifest
+// and blob GETs, anonymous, plain HTTP) for resolveFromOciMirrorRepo to
+// resolve a tag end-to-end against it.
+func fakeOciRegistry(t *testing.T, repoPath string, manifest []byte, layerContent []byte, layerDigest digest.Digest) *httptest.Server {
+	t.Helper()
+
+	manifestDigest := digest.FromBytes(manifest)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/"+repoPath+"/manifests/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+		w.Header().Set("Docker-Content-Digest", manifestDigest.String())
+		w.Write(manifest)
+	})
+	mux.HandleFunc("/v2/"+repoPath+"/blobs/"+layerDigest.String(), func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ociLayerMediaType)
+		w.Write(layerContent)
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestResolveFromOciMirror(t *testing.T) {
+	repoPath := "containerd"
+	layerContent := []byte("fake containerd tarball")
+	layerDigest := digest.FromBytes(layerContent)
+	layer := ocispec.Descriptor{
+		MediaType: ociLayerMediaType,
+		Digest:    layerDigest,
+		Size:      int64(len(layerContent)),
+	}
+	manifest, err := json.Marshal(ocispec.Manifest{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    ocispec.Descriptor{MediaType: ocispec.MediaTypeImageConfig, Digest: digest.FromBytes([]byte("{}")), Size: 2},
+		Layers:    []ocispec.Descriptor{layer},
+	})
+	if err != nil {
+		t.Fatalf("marshalling manifest: %v", err)
+	}
+
+	srv := fakeOciRegistry(t, repoPath, manifest, layerContent, layerDigest)
+	registryHost := strings.TrimPrefix(srv.URL, "http://")
+
+	// resolveFromOciMirror always talks HTTPS to the registry, which our
+	// fake registry doesn't speak; build the repo here with PlainHTTP set
+	// and exercise resolveFromOciMirrorRepo, the part of
+	// resolveFromOciMirror that does the actual resolution work.
+	repo, err := remote.NewRepository(registryHost + "/" + repoPath)
+	if err != nil {
+		t.Fatalf("remote.NewRepository() = %v", err)
+	}
+	repo.PlainHTTP = true
+
+	t.Setenv("TMPDIR", t.TempDir())
+
+	tag := fmt.Sprintf("%s-%s", "1.6.8", architectures.ArchitectureAmd64)
+	gotUrl, gotHash, err := resolveFromOciMirrorRepo(context.Background(), repo, repoPath, tag)
+	if err != nil {
+		t.Fatalf("resolveFromOciMirrorRepo() = %v", err)
+	}
+
+	wantUrl := fmt.Sprintf("http://%s/v2/%s/blobs/%s", registryHost, repoPath, layerDigest)
+	if gotUrl != wantUrl {
+		t.Errorf("resolveFromOciMirrorRepo() url = %q, want %q (must be node-fetchable, not a local path)", gotUrl, wantUrl)
+	}
+	if strings.HasPrefix(gotUrl, "file://") {
+		t.Errorf("resolveFromOciMirrorRepo() returned a local file:// url %q; a provisioned node can't read that", gotUrl)
+	}
+	if gotHash != layerDigest.Encoded() {
+		t.Errorf("resolveFromOciMirrorRepo() hash = %q, want %q", gotHash, layerDigest.Encoded())
+	}
+}
+
+func TestFetchOciLayerVerifiesDigest(t *testing.T) {
+	layerContent := []byte("fake runtime tarball")
+	goodDigest := digest.FromBytes(layerContent)
+	layer := ocispec.Descriptor{MediaType: ociLayerMediaType, Digest: goodDigest, Size: int64(len(layerContent))}
+
+	repoPath := "containerd"
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/"+repoPath+"/blobs/"+goodDigest.String(), func(w http.ResponseWriter, r *http.Request) {
+		// Serve content of the expected length but with altered bytes, so
+		// the fetch itself succeeds and only the digest check can fail.
+		tampered := append([]byte(nil), layerContent...)
+		tampered[0] ^= 0xff
+		w.Write(tampered)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	repo, err := remote.NewRepository(strings.TrimPrefix(srv.URL, "http://") + "/" + repoPath)
+	if err != nil {
+		t.Fatalf("remote.NewRepository() = %v", err)
+	}
+	repo.PlainHTTP = true
+
+	dir := t.TempDir()
+	localPath := filepath.Join(dir, goodDigest.Encoded())
+	err = fetchOciLayer(context.Background(), repo, layer, localPath)
+	if err == nil {
+		t.Fatal("fetchOciLayer() with tampered content = nil error, want a digest mismatch error")
+	}
+	if _, statErr := os.Stat(localPath); !os.IsNotExist(statErr) {
+		t.Errorf("fetchOciLayer() left a cache entry at %s for content that failed digest verification", localPath)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("fetchOciLayer() left %d stray file(s) in %s after a failed verification, want temp file cleaned up", len(entries), dir)
+	}
+}
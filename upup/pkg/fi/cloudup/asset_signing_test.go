@@ -0,0 +1,73 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestVerifyAssetSignatureNoOpByDefault(t *testing.T) {
+	grid := []struct {
+		name string
+		c    *kops.Cluster
+	}{
+		{"nil assets spec", &kops.Cluster{}},
+		{"verifySignatures unset", &kops.Cluster{Spec: kops.ClusterSpec{Assets: &kops.AssetsSpec{}}}},
+	}
+
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			// assetUrl is deliberately unreachable: if verifyAssetSignature
+			// isn't a no-op here, this would try a real network fetch and
+			// fail, not silently pass.
+			if err := verifyAssetSignature(g.c, "containerd", "https://unreachable.invalid/asset.tar.gz", false); err != nil {
+				t.Errorf("verifyAssetSignature() = %v, want nil when verification isn't opted into", err)
+			}
+		})
+	}
+}
+
+func TestVerifyAssetSignatureRejectsOciMirror(t *testing.T) {
+	c := &kops.Cluster{Spec: kops.ClusterSpec{Assets: &kops.AssetsSpec{VerifySignatures: fi.Bool(true)}}}
+
+	// assetUrl is deliberately unreachable: fromOciMirror must reject this
+	// combination before ever attempting a fetch, since an OCI registry
+	// blob endpoint has no sibling .sig/.cert to find.
+	err := verifyAssetSignature(c, "containerd", "https://registry.invalid/v2/containerd/blobs/sha256:abc", true)
+	if err == nil {
+		t.Fatal("verifyAssetSignature() with fromOciMirror=true = nil error, want a rejection")
+	}
+	if !strings.Contains(err.Error(), "ociMirror") {
+		t.Errorf("verifyAssetSignature() error = %q, want it to name the ociMirror/verifySignatures combination", err)
+	}
+}
+
+func TestDefaultSigstoreTrustPoliciesOnlyCoverWiredComponents(t *testing.T) {
+	// Every entry here must correspond to a component this package actually
+	// resolves and verifies, so the table can't silently claim signature
+	// coverage for a component with no findXAsset.
+	wired := map[string]bool{"containerd": true, "crio": true}
+	for component := range defaultSigstoreTrustPolicies {
+		if !wired[component] {
+			t.Errorf("defaultSigstoreTrustPolicies has an entry for %q, which this package doesn't resolve or verify", component)
+		}
+	}
+}
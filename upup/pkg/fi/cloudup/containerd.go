@@ -17,8 +17,13 @@ limitations under the License.
 package cloudup
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"net/http"
 	"net/url"
+	"strings"
+	"sync"
 
 	"github.com/blang/semver/v4"
 
@@ -32,6 +37,8 @@ import (
 const (
 	// containerd packages URLs for v1.4.x+
 	containerdVersionUrlAmd64 = "https://github.com/containerd/containerd/releases/download/v%s/cri-containerd-cni-%s-linux-amd64.tar.gz"
+	// containerd packages URLs for versions that ship an official ARM64 build
+	containerdVersionUrlArm64 = "https://github.com/containerd/containerd/releases/download/v%s/cri-containerd-cni-%s-linux-arm64.tar.gz"
 	// containerd legacy packages URLs for v1.2.x and v1.3.x
 	containerdLegacyUrlAmd64 = "https://storage.googleapis.com/cri-containerd-release/cri-containerd-%s.linux-amd64.tar.gz"
 	// containerd version that is available for both AMD64 and ARM64, used in case the selected version is not available for ARM64
@@ -61,24 +68,35 @@ func findContainerdAsset(c *kops.Cluster, assetBuilder *assets.AssetBuilder, arc
 	if version == "" {
 		return nil, nil, fmt.Errorf("unable to find containerd version")
 	}
-	assetUrl, assetHash, err := findContainerdVersionUrlHash(arch, version)
+
+	var assetUrl, assetHash string
+	var err error
+	ociMirror := c.Spec.Assets != nil && fi.StringValue(c.Spec.Assets.OciMirror) != ""
+	if ociMirror {
+		assetUrl, assetHash, err = resolveFromOciMirror(context.TODO(), fi.StringValue(c.Spec.Assets.OciMirror), "containerd", version, arch)
+	} else {
+		assetUrl, assetHash, err = findContainerdVersionUrlHash(assetBuilder, arch, version, fi.BoolValue(containerd.ResolveFromUpstream))
+	}
 	if err != nil {
 		return nil, nil, err
 	}
+	if err := verifyAssetSignature(c, "containerd", assetUrl, ociMirror); err != nil {
+		return nil, nil, err
+	}
 
 	return findAssetsUrlHash(assetBuilder, assetUrl, assetHash)
 }
 
-func findContainerdVersionUrlHash(arch architectures.Architecture, version string) (u string, h string, e error) {
+func findContainerdVersionUrlHash(assetBuilder *assets.AssetBuilder, arch architectures.Architecture, version string, resolveFromUpstream bool) (u string, h string, e error) {
 	var containerdAssetUrl, containerdAssetHash string
 
 	if findAllContainerdHashesAmd64()[version] != "" {
 		var err error
-		containerdAssetUrl, err = findContainerdVersionUrl(arch, version)
+		containerdAssetUrl, err = findContainerdVersionUrl(arch, version, resolveFromUpstream)
 		if err != nil {
 			return "", "", err
 		}
-		containerdAssetHash, err = findContainerdVersionHash(arch, version)
+		containerdAssetHash, err = findContainerdVersionHash(assetBuilder, arch, version, resolveFromUpstream)
 		if err != nil {
 			return "", "", err
 		}
@@ -96,6 +114,12 @@ func findContainerdVersionUrlHash(arch architectures.Architecture, version strin
 				return "", "", err
 			}
 			println(dv)
+		} else if resolveFromUpstream {
+			var err error
+			containerdAssetUrl, containerdAssetHash, err = resolveContainerdVersionFromUpstream(assetBuilder, arch, version)
+			if err != nil {
+				return "", "", err
+			}
 		} else {
 			return "", "", fmt.Errorf("unknown url and hash for containerd version: %s - %s", arch, version)
 		}
@@ -104,7 +128,27 @@ func findContainerdVersionUrlHash(arch architectures.Architecture, version strin
 	return containerdAssetUrl, containerdAssetHash, nil
 }
 
-func findContainerdVersionUrl(arch architectures.Architecture, version string) (string, error) {
+// containerdArm64Versions lists the containerd versions that publish an
+// official cri-containerd-cni ARM64 release tarball. There is deliberately
+// no corresponding hash table: unlike the AMD64/legacy tables below, these
+// hashes aren't hand-copied into source, since there's no way to know the
+// real upstream digest without actually downloading the release. Callers
+// must resolve and verify the hash via resolveUpstreamAsset instead, which
+// is why using the native ARM64 build requires resolveFromUpstream to be
+// set; without it, versions here still fall back to Docker's bundled
+// containerd exactly like a version with no ARM64 build at all.
+func containerdArm64Versions() map[string]bool {
+	return map[string]bool{
+		"1.5.0": true,
+		"1.5.1": true,
+		"1.5.2": true,
+		"1.5.3": true,
+		"1.5.4": true,
+		"1.5.5": true,
+	}
+}
+
+func findContainerdVersionUrl(arch architectures.Architecture, version string, resolveFromUpstream bool) (string, error) {
 	sv, err := semver.ParseTolerant(version)
 	if err != nil {
 		return "", fmt.Errorf("unable to parse version string: %q", version)
@@ -122,8 +166,10 @@ func findContainerdVersionUrl(arch architectures.Architecture, version string) (
 			u = fmt.Sprintf(containerdLegacyUrlAmd64, version)
 		}
 	case architectures.ArchitectureArm64:
-		// For now there are only official AMD64 builds, always using fallback Docker version instead
-		if findAllContainerdHashesAmd64()[version] != "" {
+		if containerdArm64Versions()[version] && resolveFromUpstream {
+			u = fmt.Sprintf(containerdVersionUrlArm64, version, version)
+		} else if findAllContainerdHashesAmd64()[version] != "" {
+			// No verified ARM64 source for this version, fall back to Docker's bundled containerd
 			if findAllContainerdDockerMappings()[version] != "" {
 				u = fmt.Sprintf(dockerVersionUrlArm64, findAllContainerdDockerMappings()[version])
 			} else {
@@ -141,7 +187,7 @@ func findContainerdVersionUrl(arch architectures.Architecture, version string) (
 	return u, nil
 }
 
-func findContainerdVersionHash(arch architectures.Architecture, version string) (string, error) {
+func findContainerdVersionHash(assetBuilder *assets.AssetBuilder, arch architectures.Architecture, version string, resolveFromUpstream bool) (string, error) {
 	sv, err := semver.ParseTolerant(version)
 	if err != nil {
 		return "", fmt.Errorf("unable to parse version string: %q", version)
@@ -155,8 +201,15 @@ func findContainerdVersionHash(arch architectures.Architecture, version string)
 	case architectures.ArchitectureAmd64:
 		h = findAllContainerdHashesAmd64()[version]
 	case architectures.ArchitectureArm64:
-		// For now there are only official AMD64 builds, always using fallback Docker version instead
-		if findAllContainerdHashesAmd64()[version] != "" {
+		if containerdArm64Versions()[version] && resolveFromUpstream {
+			assetUrl := fmt.Sprintf(containerdVersionUrlArm64, version, version)
+			_, hash, err := resolveUpstreamAsset(assetBuilder, "containerd", version, arch, assetUrl)
+			if err != nil {
+				return "", err
+			}
+			h = hash
+		} else if findAllContainerdHashesAmd64()[version] != "" {
+			// No verified ARM64 source for this version, fall back to Docker's bundled containerd
 			if findAllContainerdDockerMappings()[version] != "" {
 				h = findAllDockerHashesArm64()[findAllContainerdDockerMappings()[version]]
 			} else {
@@ -211,3 +264,138 @@ func findAllContainerdDockerMappings() map[string]string {
 
 	return versions
 }
+
+// upstreamAssetCaches memoizes resolved (url, hash) pairs per AssetBuilder,
+// keyed by component, version and arch, so that resolving a version from
+// upstream only hits GitHub once per cluster apply no matter how many times
+// it's requested, without mixing results across unrelated AssetBuilders
+// (e.g. concurrent applies of different clusters).
+//
+// Entries live for the lifetime of the process, not of the AssetBuilder: a
+// map holds a strong reference to its keys, so an AssetBuilder stored here
+// can never become unreachable and a finalizer on it would never run. Given
+// kops CLI invocations are one-shot processes and apply one AssetBuilder
+// each, this is in practice bounded by the life of a single apply anyway;
+// it's only long-running callers (e.g. a controller reusing this package
+// across many applies) that would need this evicted sooner, and none exist
+// today.
+var upstreamAssetCaches sync.Map // map[*assets.AssetBuilder]*sync.Map
+
+type upstreamAsset struct {
+	url  string
+	hash string
+}
+
+// upstreamAssetCacheFor returns the cache for assetBuilder, creating it on
+// first use.
+func upstreamAssetCacheFor(assetBuilder *assets.AssetBuilder) *sync.Map {
+	cache, _ := upstreamAssetCaches.LoadOrStore(assetBuilder, &sync.Map{})
+	return cache.(*sync.Map)
+}
+
+// resolveContainerdVersionFromUpstream looks up the URL and sha256 hash for
+// a containerd version that isn't present in findAllContainerdHashesAmd64 by
+// downloading the release's SHA256SUMS file (falling back to the tarball's
+// sibling .sha256sum file) from the containerd GitHub release. Callers must
+// only reach this when the user has opted in via
+// spec.containerd.resolveFromUpstream, since it requires network access to
+// github.com.
+func resolveContainerdVersionFromUpstream(assetBuilder *assets.AssetBuilder, arch architectures.Architecture, version string) (string, string, error) {
+	var assetUrl string
+	switch arch {
+	case architectures.ArchitectureAmd64:
+		assetUrl = fmt.Sprintf(containerdVersionUrlAmd64, version, version)
+	case architectures.ArchitectureArm64:
+		assetUrl = fmt.Sprintf(containerdVersionUrlArm64, version, version)
+	default:
+		return "", "", fmt.Errorf("unknown arch: %q", arch)
+	}
+
+	return resolveUpstreamAsset(assetBuilder, "containerd", version, arch, assetUrl)
+}
+
+// resolveUpstreamAsset resolves and caches the sha256 hash for assetUrl,
+// an upstream GitHub release tarball that isn't in any of this package's
+// built-in hash tables. The cache is scoped to assetBuilder, so results are
+// not shared across AssetBuilders; entries are never evicted (see
+// upstreamAssetCaches). component is only used as a
+// cache key and an error-message label, so this is reusable as-is for any
+// other GitHub-released component (docker, runc, cni-plugins, ...) once
+// those get their own findXAsset, the way findCrioAsset already does.
+func resolveUpstreamAsset(assetBuilder *assets.AssetBuilder, component string, version string, arch architectures.Architecture, assetUrl string) (string, string, error) {
+	cache := upstreamAssetCacheFor(assetBuilder)
+	cacheKey := fmt.Sprintf("%s/%s/%s", component, version, arch)
+	if cached, ok := cache.Load(cacheKey); ok {
+		a := cached.(upstreamAsset)
+		return a.url, a.hash, nil
+	}
+
+	hash, err := fetchUpstreamSha256(assetUrl)
+	if err != nil {
+		return "", "", fmt.Errorf("resolving %s %s from upstream: %w", component, version, err)
+	}
+
+	cache.Store(cacheKey, upstreamAsset{url: assetUrl, hash: hash})
+	return assetUrl, hash, nil
+}
+
+// fetchUpstreamSha256 downloads the SHA256SUMS manifest published alongside
+// a GitHub release tarball and returns the hash for tarballUrl's basename,
+// falling back to the tarball's sibling <name>.sha256sum file if the
+// release doesn't publish a combined checksum manifest.
+func fetchUpstreamSha256(tarballUrl string) (string, error) {
+	dir := tarballUrl[:strings.LastIndex(tarballUrl, "/")]
+	name := tarballUrl[strings.LastIndex(tarballUrl, "/")+1:]
+
+	if hash, err := fetchSha256SumsEntry(dir+"/SHA256SUMS", name); err == nil {
+		return hash, nil
+	}
+
+	hash, err := fetchSha256SumFile(tarballUrl + ".sha256sum")
+	if err != nil {
+		return "", fmt.Errorf("no SHA256SUMS or %s.sha256sum published for this release: %w", name, err)
+	}
+	return hash, nil
+}
+
+func fetchSha256SumsEntry(sumsUrl string, name string) (string, error) {
+	body, err := httpGetString(sumsUrl)
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(body, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return fields[0], nil
+		}
+	}
+	return "", fmt.Errorf("%s not found in %s", name, sumsUrl)
+}
+
+func fetchSha256SumFile(url string) (string, error) {
+	body, err := httpGetString(url)
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(body)
+	if len(fields) == 0 {
+		return "", fmt.Errorf("empty checksum file: %s", url)
+	}
+	return fields[0], nil
+}
+
+func httpGetString(url string) (string, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %d fetching %s", resp.StatusCode, url)
+	}
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
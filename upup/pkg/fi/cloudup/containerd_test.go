@@ -0,0 +1,80 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/kops/util/pkg/architectures"
+)
+
+func TestFindContainerdVersionUrlArm64(t *testing.T) {
+	grid := []struct {
+		name                string
+		version             string
+		resolveFromUpstream bool
+		wantContains        string
+	}{
+		{
+			name:                "native arm64 build without opt-in falls back to docker",
+			version:             "1.5.5",
+			resolveFromUpstream: false,
+			wantContains:        "docker",
+		},
+		{
+			name:                "native arm64 build with opt-in uses the upstream tarball",
+			version:             "1.5.5",
+			resolveFromUpstream: true,
+			wantContains:        "containerd/containerd/releases",
+		},
+		{
+			name:                "no native arm64 build always falls back to docker, regardless of opt-in",
+			version:             "1.4.6",
+			resolveFromUpstream: true,
+			wantContains:        "docker",
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			u, err := findContainerdVersionUrl(architectures.ArchitectureArm64, g.version, g.resolveFromUpstream)
+			if err != nil {
+				t.Fatalf("findContainerdVersionUrl(%q, %v): unexpected error: %v", g.version, g.resolveFromUpstream, err)
+			}
+			if !strings.Contains(u, g.wantContains) {
+				t.Errorf("findContainerdVersionUrl(%q, %v) = %q, want substring %q", g.version, g.resolveFromUpstream, u, g.wantContains)
+			}
+		})
+	}
+}
+
+func TestContainerdArm64VersionsHasNoHashTable(t *testing.T) {
+	// containerdArm64Versions is a version-availability list, not a hash
+	// table: it must report native ARM64 availability without ever
+	// claiming a hash, since every hash in this file must come from
+	// resolveUpstreamAsset instead of being hand-typed into source.
+	versions := containerdArm64Versions()
+	if len(versions) == 0 {
+		t.Fatal("containerdArm64Versions() returned no versions")
+	}
+	for version, hasNativeBuild := range versions {
+		if !hasNativeBuild {
+			t.Errorf("containerdArm64Versions()[%q] = false, entries should only be present when true", version)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/util/pkg/architectures"
+)
+
+func TestFindCrioVersionUrl(t *testing.T) {
+	grid := []struct {
+		arch         architectures.Architecture
+		wantContains string
+	}{
+		{architectures.ArchitectureAmd64, "cri-o.amd64.1.23.0.tar.gz"},
+		{architectures.ArchitectureArm64, "cri-o.arm64.1.23.0.tar.gz"},
+	}
+
+	for _, g := range grid {
+		u, err := findCrioVersionUrl(g.arch, "1.23.0")
+		if err != nil {
+			t.Fatalf("findCrioVersionUrl(%q): unexpected error: %v", g.arch, err)
+		}
+		if !strings.Contains(u, g.wantContains) {
+			t.Errorf("findCrioVersionUrl(%q) = %q, want substring %q", g.arch, u, g.wantContains)
+		}
+	}
+}
+
+func TestFindCrioVersionUrlHashRequiresOptIn(t *testing.T) {
+	assetBuilder := &assets.AssetBuilder{}
+
+	if _, _, err := findCrioVersionUrlHash(assetBuilder, architectures.ArchitectureAmd64, "1.23.0", false); err == nil {
+		t.Fatal("findCrioVersionUrlHash with resolveFromUpstream=false should error instead of silently resolving, but it didn't")
+	}
+}
+
+func TestFindContainerRuntimeAssetUnknownRuntime(t *testing.T) {
+	c := &kops.Cluster{Spec: kops.ClusterSpec{ContainerRuntime: "not-a-real-runtime"}}
+	assetBuilder := &assets.AssetBuilder{}
+
+	if _, _, err := findContainerRuntimeAsset(c, assetBuilder, architectures.ArchitectureAmd64); err == nil {
+		t.Fatal("findContainerRuntimeAsset with an unknown runtime should error, but it didn't")
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sigstore verifies artifacts fetched from GitHub releases against
+// cosign keyless signatures published to the public Rekor transparency log.
+// It is used by the cloudup asset resolvers to confirm that a downloaded
+// runtime tarball was actually built and signed by the expected project,
+// rather than trusting a baked-in sha256 alone.
+package sigstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+)
+
+// TrustPolicy pins the OIDC identity that a signature must chain back to
+// before an artifact is accepted. Both fields are matched against the
+// Fulcio certificate's SAN and issuer extension.
+type TrustPolicy struct {
+	// Issuer is the expected OIDC issuer, e.g. "https://token.actions.githubusercontent.com".
+	Issuer string
+	// Subject is the expected signing identity, e.g.
+	// "https://github.com/containerd/containerd/.github/workflows/release.yml@refs/heads/main".
+	Subject string
+}
+
+// VerifyArtifact downloads the .sig and .cert files published alongside
+// artifactUrl in the same GitHub release and verifies the detached
+// signature against the Rekor transparency log, checking that the signing
+// certificate matches the given trust policy. It returns an error if the
+// signature, certificate, or Rekor inclusion proof don't validate, or if
+// the certificate identity doesn't match policy.
+func VerifyArtifact(ctx context.Context, artifactUrl string, policy TrustPolicy) error {
+	sig, err := fetch(artifactUrl + ".sig")
+	if err != nil {
+		return fmt.Errorf("fetching signature for %s: %w", artifactUrl, err)
+	}
+	cert, err := fetch(artifactUrl + ".cert")
+	if err != nil {
+		return fmt.Errorf("fetching certificate for %s: %w", artifactUrl, err)
+	}
+	blob, err := fetch(artifactUrl)
+	if err != nil {
+		return fmt.Errorf("fetching artifact %s: %w", artifactUrl, err)
+	}
+
+	parsedCert, err := cryptoutils.UnmarshalCertificatesFromPEM(cert)
+	if err != nil || len(parsedCert) == 0 {
+		return fmt.Errorf("parsing signing certificate for %s: %w", artifactUrl, err)
+	}
+
+	co := &cosign.CheckOpts{
+		Identities: []cosign.Identity{
+			{Issuer: policy.Issuer, Subject: policy.Subject},
+		},
+		RekorPubKeys: cosign.GetRekorPubs(ctx),
+		CTLogPubKeys: cosign.GetCTLogPubs(ctx),
+	}
+
+	if err := cosign.VerifyBlobSignature(ctx, blob, sig, parsedCert[0], co); err != nil {
+		return fmt.Errorf("verifying signature for %s against %s/%s: %w", artifactUrl, policy.Issuer, policy.Subject, err)
+	}
+
+	return nil
+}
+
+func fetch(rawUrl string) ([]byte, error) {
+	if _, err := url.ParseRequestURI(rawUrl); err != nil {
+		return nil, fmt.Errorf("invalid url %q: %w", rawUrl, err)
+	}
+	resp, err := http.Get(rawUrl)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d fetching %s", resp.StatusCode, rawUrl)
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sigstore
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFetch(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("artifact-bytes"))
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	t.Run("invalid url", func(t *testing.T) {
+		if _, err := fetch(":not a url"); err == nil {
+			t.Fatal("fetch() with an invalid url = nil error, want one")
+		}
+	})
+
+	t.Run("non-200 status", func(t *testing.T) {
+		_, err := fetch(srv.URL + "/missing")
+		if err == nil {
+			t.Fatal("fetch() against a 404 = nil error, want one")
+		}
+		if !strings.Contains(err.Error(), "404") {
+			t.Errorf("fetch() error = %q, want it to mention the status code", err)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		body, err := fetch(srv.URL + "/ok")
+		if err != nil {
+			t.Fatalf("fetch() = %v", err)
+		}
+		if string(body) != "artifact-bytes" {
+			t.Errorf("fetch() body = %q, want %q", body, "artifact-bytes")
+		}
+	})
+}
+
+func TestVerifyArtifactWrapsFetchErrors(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/artifact.tar.gz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("blob"))
+	})
+	mux.HandleFunc("/artifact.tar.gz.sig", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("sig"))
+	})
+	mux.HandleFunc("/artifact.tar.gz.cert", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("not a pem certificate"))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	policy := TrustPolicy{Issuer: "https://token.actions.githubusercontent.com", Subject: "https://github.com/example/example/.github/workflows/release.yml@refs/heads/main"}
+
+	t.Run("missing signature", func(t *testing.T) {
+		err := VerifyArtifact(context.Background(), srv.URL+"/does-not-exist.tar.gz", policy)
+		if err == nil {
+			t.Fatal("VerifyArtifact() with no .sig published = nil error, want one")
+		}
+		if !strings.Contains(err.Error(), "fetching signature") {
+			t.Errorf("VerifyArtifact() error = %q, want it to identify the missing signature", err)
+		}
+	})
+
+	t.Run("invalid certificate", func(t *testing.T) {
+		err := VerifyArtifact(context.Background(), srv.URL+"/artifact.tar.gz", policy)
+		if err == nil {
+			t.Fatal("VerifyArtifact() with an unparseable .cert = nil error, want one")
+		}
+		if !strings.Contains(err.Error(), "parsing signing certificate") {
+			t.Errorf("VerifyArtifact() error = %q, want it to identify the bad certificate", err)
+		}
+	})
+}